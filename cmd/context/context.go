@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package context implements the `godpu context` subcommand for managing
+// named OPI server connection profiles.
+package context
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/opiproject/godpu/pkg/cliconfig"
+	"github.com/spf13/cobra"
+)
+
+// NewContextCommand returns the `godpu context` command tree.
+func NewContextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage named OPI server connection profiles",
+	}
+
+	cmd.AddCommand(newSetCommand())
+	cmd.AddCommand(newUseCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newDeleteCommand())
+	return cmd
+}
+
+func newSetCommand() *cobra.Command {
+	var addr string
+	var certFile, keyFile, caFile, serverName string
+	var timeout time.Duration
+	var pageSize int32
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create or update a named connection profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			cfg, err := cliconfig.Load()
+			if err != nil {
+				log.Fatalf("failed to load config: %v", err)
+			}
+
+			profile := cliconfig.Profile{
+				Addr:            addr,
+				Timeout:         timeout,
+				DefaultPageSize: pageSize,
+			}
+			if certFile != "" || keyFile != "" || caFile != "" || serverName != "" {
+				profile.TLS = &cliconfig.TLS{
+					CertFile:           certFile,
+					KeyFile:            keyFile,
+					CAFile:             caFile,
+					ServerNameOverride: serverName,
+				}
+			}
+
+			cfg.Contexts[args[0]] = profile
+			if err := cliconfig.Save(cfg); err != nil {
+				log.Fatalf("failed to save config: %v", err)
+			}
+			log.Printf("Context %q set\n", args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
+	cmd.Flags().StringVar(&certFile, "tls-cert", "", "client certificate for mTLS")
+	cmd.Flags().StringVar(&keyFile, "tls-key", "", "client key for mTLS")
+	cmd.Flags().StringVar(&caFile, "tls-ca", "", "CA bundle used to verify the server certificate")
+	cmd.Flags().StringVar(&serverName, "tls-server-name", "", "override the server name verified in the TLS certificate")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "request timeout for commands using this context")
+	cmd.Flags().Int32Var(&pageSize, "default-page-size", 0, "default page size for list commands using this context")
+
+	return cmd
+}
+
+func newUseCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the active connection profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			cfg, err := cliconfig.Load()
+			if err != nil {
+				log.Fatalf("failed to load config: %v", err)
+			}
+			if _, ok := cfg.Contexts[args[0]]; !ok {
+				log.Fatalf("context %q not found; run %q to see what's available", args[0], "godpu context list")
+			}
+			cfg.CurrentContext = args[0]
+			if err := cliconfig.Save(cfg); err != nil {
+				log.Fatalf("failed to save config: %v", err)
+			}
+			log.Printf("Switched to context %q\n", args[0])
+		},
+	}
+}
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the known connection profiles",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			cfg, err := cliconfig.Load()
+			if err != nil {
+				log.Fatalf("failed to load config: %v", err)
+			}
+
+			names := make([]string, 0, len(cfg.Contexts))
+			for name := range cfg.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				marker := " "
+				if name == cfg.CurrentContext {
+					marker = "*"
+				}
+				fmt.Printf("%s %s\t%s\n", marker, name, cfg.Contexts[name].Addr)
+			}
+		},
+	}
+}
+
+func newDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a connection profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			cfg, err := cliconfig.Load()
+			if err != nil {
+				log.Fatalf("failed to load config: %v", err)
+			}
+			if _, ok := cfg.Contexts[args[0]]; !ok {
+				log.Fatalf("context %q not found", args[0])
+			}
+			delete(cfg.Contexts, args[0])
+			if cfg.CurrentContext == args[0] {
+				cfg.CurrentContext = ""
+			}
+			if err := cliconfig.Save(cfg); err != nil {
+				log.Fatalf("failed to save config: %v", err)
+			}
+			log.Printf("Deleted context %q\n", args[0])
+		},
+	}
+}
@@ -0,0 +1,719 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2022-2023 Intel Corporation, or its subsidiaries.
+// Copyright (c) 2022-2023 Dell Inc, or its subsidiaries.
+// Copyright (c) 2024 Ericsson AB.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/opiproject/godpu/network"
+	"github.com/opiproject/godpu/pkg/cliconfig"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// resourceKind identifies the kind of EVPN object described in a topology document.
+type resourceKind string
+
+const (
+	kindVrf           resourceKind = "Vrf"
+	kindLogicalBridge resourceKind = "LogicalBridge"
+	kindBridgePort    resourceKind = "BridgePort"
+	kindSvi           resourceKind = "Svi"
+)
+
+// topologyDoc is a single `---`-separated YAML document inside a topology file.
+type topologyDoc struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       resourceKind           `yaml:"kind"`
+	Metadata   topologyMetadata       `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+type topologyMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// fabric is a fully loaded topology, grouped by kind in apply order.
+type fabric struct {
+	vrfs           []topologyDoc
+	logicalBridges []topologyDoc
+	bridgePorts    []topologyDoc
+	svis           []topologyDoc
+}
+
+// loadFabric reads one or more topology files and groups their documents by kind.
+// Documents are kept in the order they were declared so that within a kind the
+// user's own ordering (e.g. for readability) is preserved.
+func loadFabric(paths []string) (*fabric, error) {
+	f := &fabric{}
+	names := map[string]resourceKind{}
+
+	for _, path := range paths {
+		file, err := os.Open(path) //nolint:gosec // topology file path comes from an explicit CLI flag
+		if err != nil {
+			return nil, fmt.Errorf("failed to open topology file %q: %w", path, err)
+		}
+
+		dec := yaml.NewDecoder(file)
+		for {
+			var doc topologyDoc
+			if err := dec.Decode(&doc); err != nil {
+				if err.Error() == "EOF" {
+					break
+				}
+				_ = file.Close()
+				return nil, fmt.Errorf("failed to parse topology file %q: %w", path, err)
+			}
+			if doc.Kind == "" {
+				continue // skip empty documents between `---` separators
+			}
+			if doc.Metadata.Name == "" {
+				_ = file.Close()
+				return nil, fmt.Errorf("%s in %q is missing metadata.name", doc.Kind, path)
+			}
+			if existing, ok := names[doc.Metadata.Name]; ok {
+				_ = file.Close()
+				return nil, fmt.Errorf("duplicate object name %q (%s and %s)", doc.Metadata.Name, existing, doc.Kind)
+			}
+			names[doc.Metadata.Name] = doc.Kind
+
+			switch doc.Kind {
+			case kindVrf:
+				f.vrfs = append(f.vrfs, doc)
+			case kindLogicalBridge:
+				f.logicalBridges = append(f.logicalBridges, doc)
+			case kindBridgePort:
+				f.bridgePorts = append(f.bridgePorts, doc)
+			case kindSvi:
+				f.svis = append(f.svis, doc)
+			default:
+				_ = file.Close()
+				return nil, fmt.Errorf("%q: unknown kind %q", path, doc.Kind)
+			}
+		}
+		if err := file.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close topology file %q: %w", path, err)
+		}
+	}
+
+	if err := resolveRefs(f, names); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// resolveRefs checks that every cross-reference (a spec field naming another
+// object, e.g. a BridgePort's `logicalBridge`) points at an object that is
+// actually declared in the fabric, so that dangling references are reported
+// up front rather than surfacing as a confusing gRPC NotFound mid-apply.
+func resolveRefs(f *fabric, names map[string]resourceKind) error {
+	checkRef := func(owner, field string, want resourceKind, value interface{}) error {
+		if value == nil {
+			return nil
+		}
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: field %q must be an object name", owner, field)
+		}
+		kind, ok := names[name]
+		if !ok {
+			return fmt.Errorf("%s: %s %q references unknown object %q", owner, field, name, name)
+		}
+		if kind != want {
+			return fmt.Errorf("%s: %s %q refers to a %s, expected a %s", owner, field, name, kind, want)
+		}
+		return nil
+	}
+
+	for _, bp := range f.bridgePorts {
+		if err := checkRef(bp.Metadata.Name, "logicalBridge", kindLogicalBridge, bp.Spec["logicalBridge"]); err != nil {
+			return err
+		}
+	}
+	for _, svi := range f.svis {
+		if err := checkRef(svi.Metadata.Name, "vrf", kindVrf, svi.Spec["vrf"]); err != nil {
+			return err
+		}
+		if err := checkRef(svi.Metadata.Name, "logicalBridge", kindLogicalBridge, svi.Spec["logicalBridge"]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply reconciles an EVPN fabric described by one or more YAML topology
+// files against the OPI gRPC server.
+func Apply() *cobra.Command {
+	var files []string
+	var dryRun bool
+	var dialFlags *cliconfig.DialFlags
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a declarative EVPN topology",
+		Long: "Apply reconciles the VRFs, logical bridges, bridge ports and SVIs described\n" +
+			"in one or more YAML topology files against the OPI gRPC server. Objects are\n" +
+			"created or updated in dependency order (VRFs and logical bridges first,\n" +
+			"then bridge ports and SVIs), and objects whose observed spec already\n" +
+			"matches are left untouched.",
+		Run: func(c *cobra.Command, _ []string) {
+			f, err := loadFabric(files)
+			if err != nil {
+				log.Fatalf("failed to load topology: %v", err)
+			}
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			if err := reconcile(profile, f, dryRun); err != nil {
+				log.Fatalf("apply failed: %v", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Topology YAML file (repeatable)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the planned Create/Update/Delete calls without executing them")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+
+	if err := cmd.MarkFlagRequired("filename"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+	return cmd
+}
+
+// Destroy tears down the EVPN fabric described by one or more YAML topology
+// files, in the reverse of the order Apply would create them.
+func Destroy() *cobra.Command {
+	var files []string
+	var dryRun bool
+	var dialFlags *cliconfig.DialFlags
+
+	cmd := &cobra.Command{
+		Use:   "destroy",
+		Short: "Destroy a declarative EVPN topology",
+		Run: func(c *cobra.Command, _ []string) {
+			f, err := loadFabric(files)
+			if err != nil {
+				log.Fatalf("failed to load topology: %v", err)
+			}
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			if err := teardown(profile, f, dryRun); err != nil {
+				log.Fatalf("destroy failed: %v", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Topology YAML file (repeatable)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the planned Delete calls without executing them")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+
+	if err := cmd.MarkFlagRequired("filename"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+	return cmd
+}
+
+// reconcile walks the fabric in dependency order, creating objects that don't
+// exist yet and updating (via a computed FieldMask) VRFs and bridge ports
+// whose observed spec has drifted from the desired one. Logical bridges and
+// SVIs that already exist are left as they are.
+func reconcile(profile cliconfig.Profile, f *fabric, dryRun bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(30*time.Second))
+	defer cancel()
+
+	dialOpts, err := profile.DialOptions()
+	if err != nil {
+		return fmt.Errorf("could not build dial options: %w", err)
+	}
+
+	vrfClient, err := network.NewVRF(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+
+	bridgeClient, err := network.NewBridge(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	bridgePortClient, err := network.NewBridgePort(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	sviClient, err := network.NewSvi(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+
+	for _, doc := range f.vrfs {
+		if err := reconcileVrf(ctx, vrfClient, doc, dryRun); err != nil {
+			return err
+		}
+	}
+	for _, doc := range f.logicalBridges {
+		if err := reconcileLogicalBridge(ctx, bridgeClient, doc, dryRun); err != nil {
+			return err
+		}
+	}
+	for _, doc := range f.bridgePorts {
+		if err := reconcileBridgePort(ctx, bridgePortClient, doc, dryRun); err != nil {
+			return err
+		}
+	}
+	for _, doc := range f.svis {
+		if err := reconcileSvi(ctx, sviClient, doc, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teardown deletes fabric objects in the reverse of apply order: SVIs and
+// bridge ports first, then logical bridges and VRFs, using allow_missing
+// semantics so a partially-applied fabric can always be cleaned up.
+func teardown(profile cliconfig.Profile, f *fabric, dryRun bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(30*time.Second))
+	defer cancel()
+
+	dialOpts, err := profile.DialOptions()
+	if err != nil {
+		return fmt.Errorf("could not build dial options: %w", err)
+	}
+
+	sviClient, err := network.NewSvi(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	for i := len(f.svis) - 1; i >= 0; i-- {
+		if err := destroySvi(ctx, sviClient, f.svis[i], dryRun); err != nil {
+			return err
+		}
+	}
+
+	bridgePortClient, err := network.NewBridgePort(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	for i := len(f.bridgePorts) - 1; i >= 0; i-- {
+		if err := destroyBridgePort(ctx, bridgePortClient, f.bridgePorts[i], dryRun); err != nil {
+			return err
+		}
+	}
+
+	bridgeClient, err := network.NewBridge(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	for i := len(f.logicalBridges) - 1; i >= 0; i-- {
+		if err := destroyLogicalBridge(ctx, bridgeClient, f.logicalBridges[i], dryRun); err != nil {
+			return err
+		}
+	}
+
+	vrfClient, err := network.NewVRF(profile.Addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not create gRPC client: %w", err)
+	}
+	for i := len(f.vrfs) - 1; i >= 0; i-- {
+		if err := destroyVrf(ctx, vrfClient, f.vrfs[i], dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vrfDesired is the set of VRF spec fields a topology document can declare,
+// mirroring the flags create-vrf/update-vrf accept.
+type vrfDesired struct {
+	vni         *uint32
+	loopback    string
+	vtep        string
+	localASN    uint32
+	importRT    []string
+	exportRT    []string
+	ipRange     []string
+	description string
+	tags        map[string]string
+}
+
+// vrfSpecFromDoc extracts the VRF fields a topology document declares.
+func vrfSpecFromDoc(doc topologyDoc) vrfDesired {
+	var desired vrfDesired
+	if vni, ok := specUint32(doc.Spec["vni"]); ok {
+		desired.vni = &vni
+	}
+	desired.loopback, _ = doc.Spec["loopback"].(string)
+	desired.vtep, _ = doc.Spec["vtep"].(string)
+	desired.localASN, _ = specUint32(doc.Spec["localAsn"])
+	desired.importRT = specStringSlice(doc.Spec["importRt"])
+	desired.exportRT = specStringSlice(doc.Spec["exportRt"])
+	desired.ipRange = specStringSlice(doc.Spec["ipRange"])
+	desired.description, _ = doc.Spec["description"].(string)
+	desired.tags = specStringMap(doc.Spec["tags"])
+	return desired
+}
+
+// reconcileVrf creates the VRF if it doesn't exist yet, or updates it with a
+// FieldMask computed from the spec fields that actually drifted.
+func reconcileVrf(ctx context.Context, c *network.VRF, doc topologyDoc, dryRun bool) error {
+	desired := vrfSpecFromDoc(doc)
+
+	existing, err := c.GetVrf(ctx, doc.Metadata.Name)
+	if status.Code(err) == codes.NotFound {
+		planf(dryRun, "Vrf", doc.Metadata.Name, "create")
+		if dryRun {
+			return nil
+		}
+		_, err := c.CreateVrf(ctx, doc.Metadata.Name, desired.vni, desired.loopback, desired.vtep,
+			desired.localASN, desired.importRT, desired.exportRT, desired.ipRange, desired.description, desired.tags)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up vrf %q: %w", doc.Metadata.Name, err)
+	}
+
+	mask := vrfUpdateMask(existing, desired)
+	if len(mask) == 0 {
+		planf(dryRun, "Vrf", doc.Metadata.Name, "up to date, skipping")
+		return nil
+	}
+	planf(dryRun, "Vrf", doc.Metadata.Name, fmt.Sprintf("update FieldMask=%v", mask))
+	if dryRun {
+		return nil
+	}
+	_, err = c.UpdateVrf(ctx, doc.Metadata.Name, mask, false,
+		desired.loopback, desired.vtep, desired.localASN, desired.importRT, desired.exportRT,
+		desired.ipRange, desired.description, desired.tags)
+	return err
+}
+
+// destroyVrf deletes a VRF, allowing it to already be missing.
+func destroyVrf(ctx context.Context, c *network.VRF, doc topologyDoc, dryRun bool) error {
+	planf(dryRun, "Vrf", doc.Metadata.Name, "delete (allow_missing)")
+	if dryRun {
+		return nil
+	}
+	_, err := c.DeleteVrf(ctx, doc.Metadata.Name, true)
+	return err
+}
+
+// bridgeDesired is the set of LogicalBridge spec fields a topology document
+// can declare.
+type bridgeDesired struct {
+	vlanID uint32
+	vni    *uint32
+}
+
+// bridgeSpecFromDoc extracts the LogicalBridge fields a topology document declares.
+func bridgeSpecFromDoc(doc topologyDoc) bridgeDesired {
+	var desired bridgeDesired
+	desired.vlanID, _ = specUint32(doc.Spec["vlanId"])
+	if vni, ok := specUint32(doc.Spec["vni"]); ok {
+		desired.vni = &vni
+	}
+	return desired
+}
+
+// reconcileLogicalBridge creates the logical bridge if it doesn't exist yet.
+// LogicalBridge has no update path, so an object that's already present is
+// left as-is regardless of spec drift.
+func reconcileLogicalBridge(ctx context.Context, c *network.Bridge, doc topologyDoc, dryRun bool) error {
+	desired := bridgeSpecFromDoc(doc)
+
+	_, err := c.GetLogicalBridge(ctx, doc.Metadata.Name)
+	if status.Code(err) == codes.NotFound {
+		planf(dryRun, "LogicalBridge", doc.Metadata.Name, "create")
+		if dryRun {
+			return nil
+		}
+		_, err := c.CreateLogicalBridge(ctx, doc.Metadata.Name, desired.vlanID, desired.vni)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up logical bridge %q: %w", doc.Metadata.Name, err)
+	}
+	planf(dryRun, "LogicalBridge", doc.Metadata.Name, "up to date, skipping (no update support)")
+	return nil
+}
+
+// destroyLogicalBridge deletes a logical bridge, allowing it to already be missing.
+func destroyLogicalBridge(ctx context.Context, c *network.Bridge, doc topologyDoc, dryRun bool) error {
+	planf(dryRun, "LogicalBridge", doc.Metadata.Name, "delete (allow_missing)")
+	if dryRun {
+		return nil
+	}
+	_, err := c.DeleteLogicalBridge(ctx, doc.Metadata.Name, true)
+	return err
+}
+
+// bridgePortDesired is the set of BridgePort spec fields a topology document
+// can declare.
+type bridgePortDesired struct {
+	logicalBridge string
+	macAddress    string
+}
+
+// bridgePortSpecFromDoc extracts the BridgePort fields a topology document declares.
+func bridgePortSpecFromDoc(doc topologyDoc) bridgePortDesired {
+	var desired bridgePortDesired
+	desired.logicalBridge, _ = doc.Spec["logicalBridge"].(string)
+	desired.macAddress, _ = doc.Spec["macAddress"].(string)
+	return desired
+}
+
+// bridgePortUpdateMask diffs the desired spec against the observed
+// *network.BridgePort returned by GetBridgePort and returns the proto field
+// paths that actually drifted, mirroring vrfUpdateMask. It takes the real
+// generated type rather than a hand-written interface: protobuf getters
+// return concrete struct pointers (e.g. GetSpec() *network.BridgePortSpec),
+// and Go only satisfies an interface by an exact method signature match, so
+// a local interface declaring GetSpec() as returning some other interface
+// type can never be satisfied by it.
+func bridgePortUpdateMask(existing *network.BridgePort, desired bridgePortDesired) []string {
+	if existing == nil {
+		return nil
+	}
+	spec := existing.GetSpec()
+
+	var mask []string
+	if desired.logicalBridge != "" && desired.logicalBridge != spec.GetLogicalBridge() {
+		mask = append(mask, "spec.logical_bridge")
+	}
+	if desired.macAddress != "" && desired.macAddress != spec.GetMacAddress() {
+		mask = append(mask, "spec.mac_address")
+	}
+	return mask
+}
+
+// reconcileBridgePort creates the bridge port if it doesn't exist yet, or
+// updates it with a FieldMask computed from the spec fields that actually
+// drifted.
+func reconcileBridgePort(ctx context.Context, c *network.BridgePortClient, doc topologyDoc, dryRun bool) error {
+	desired := bridgePortSpecFromDoc(doc)
+
+	existing, err := c.GetBridgePort(ctx, doc.Metadata.Name)
+	if status.Code(err) == codes.NotFound {
+		planf(dryRun, "BridgePort", doc.Metadata.Name, "create")
+		if dryRun {
+			return nil
+		}
+		_, err := c.CreateBridgePort(ctx, doc.Metadata.Name, desired.logicalBridge, desired.macAddress)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up bridge port %q: %w", doc.Metadata.Name, err)
+	}
+
+	mask := bridgePortUpdateMask(existing, desired)
+	if len(mask) == 0 {
+		planf(dryRun, "BridgePort", doc.Metadata.Name, "up to date, skipping")
+		return nil
+	}
+	planf(dryRun, "BridgePort", doc.Metadata.Name, fmt.Sprintf("update FieldMask=%v", mask))
+	if dryRun {
+		return nil
+	}
+	_, err = c.UpdateBridgePort(ctx, doc.Metadata.Name, mask, false, desired.logicalBridge, desired.macAddress)
+	return err
+}
+
+// destroyBridgePort deletes a bridge port, allowing it to already be missing.
+func destroyBridgePort(ctx context.Context, c *network.BridgePortClient, doc topologyDoc, dryRun bool) error {
+	planf(dryRun, "BridgePort", doc.Metadata.Name, "delete (allow_missing)")
+	if dryRun {
+		return nil
+	}
+	_, err := c.DeleteBridgePort(ctx, doc.Metadata.Name, true)
+	return err
+}
+
+// sviDesired is the set of Svi spec fields a topology document can declare.
+type sviDesired struct {
+	vrf           string
+	logicalBridge string
+	gatewayIPs    []string
+}
+
+// sviSpecFromDoc extracts the Svi fields a topology document declares.
+func sviSpecFromDoc(doc topologyDoc) sviDesired {
+	var desired sviDesired
+	desired.vrf, _ = doc.Spec["vrf"].(string)
+	desired.logicalBridge, _ = doc.Spec["logicalBridge"].(string)
+	desired.gatewayIPs = specStringSlice(doc.Spec["gatewayIPs"])
+	return desired
+}
+
+// reconcileSvi creates the SVI if it doesn't exist yet. Svi has no update
+// path, so an object that's already present is left as-is regardless of
+// spec drift.
+func reconcileSvi(ctx context.Context, c *network.Svi, doc topologyDoc, dryRun bool) error {
+	desired := sviSpecFromDoc(doc)
+
+	_, err := c.GetSvi(ctx, doc.Metadata.Name)
+	if status.Code(err) == codes.NotFound {
+		planf(dryRun, "Svi", doc.Metadata.Name, "create")
+		if dryRun {
+			return nil
+		}
+		_, err := c.CreateSvi(ctx, doc.Metadata.Name, desired.vrf, desired.logicalBridge, desired.gatewayIPs)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up svi %q: %w", doc.Metadata.Name, err)
+	}
+	planf(dryRun, "Svi", doc.Metadata.Name, "up to date, skipping (no update support)")
+	return nil
+}
+
+// destroySvi deletes an SVI, allowing it to already be missing.
+func destroySvi(ctx context.Context, c *network.Svi, doc topologyDoc, dryRun bool) error {
+	planf(dryRun, "Svi", doc.Metadata.Name, "delete (allow_missing)")
+	if dryRun {
+		return nil
+	}
+	_, err := c.DeleteSvi(ctx, doc.Metadata.Name, true)
+	return err
+}
+
+// vrfUpdateMask diffs the desired spec against the observed *network.Vrf
+// returned by GetVrf and returns the proto field paths that actually
+// drifted, so that an already-correct VRF is left untouched instead of
+// re-sent on every apply. A field the topology document doesn't set is
+// never considered drifted, since an unset field means "leave as-is", not
+// "reset to zero".
+//
+// This takes the real generated type rather than a hand-written interface:
+// protobuf getters return concrete struct pointers (e.g. GetSpec()
+// *network.VrfSpec), and Go only satisfies an interface by an exact method
+// signature match, so a local interface declaring GetSpec() as returning
+// some other interface type can never be satisfied by it.
+func vrfUpdateMask(existing *network.Vrf, desired vrfDesired) []string {
+	if existing == nil {
+		return nil
+	}
+	spec := existing.GetSpec()
+
+	// VNI is intentionally left out of this diff: it's only ever sent on
+	// CreateVrf (see reconcileVrf above); UpdateVrf has no way to carry a new
+	// VNI value, so a mask entry for it could never actually be applied.
+	var mask []string
+	if desired.loopback != "" && desired.loopback != spec.GetLoopback() {
+		mask = append(mask, "spec.loopback")
+	}
+	if desired.vtep != "" && desired.vtep != spec.GetVtep() {
+		mask = append(mask, "spec.vtep")
+	}
+	if desired.localASN != 0 && desired.localASN != spec.GetLocalAsn() {
+		mask = append(mask, "spec.local_asn")
+	}
+	if len(desired.importRT) > 0 && !equalStringSlices(desired.importRT, spec.GetImportRt()) {
+		mask = append(mask, "spec.import_rt")
+	}
+	if len(desired.exportRT) > 0 && !equalStringSlices(desired.exportRT, spec.GetExportRt()) {
+		mask = append(mask, "spec.export_rt")
+	}
+	if len(desired.ipRange) > 0 && !equalStringSlices(desired.ipRange, spec.GetIpRange()) {
+		mask = append(mask, "spec.ip_range")
+	}
+	if desired.description != "" && desired.description != spec.GetDescription() {
+		mask = append(mask, "spec.description")
+	}
+	if len(desired.tags) > 0 && !equalStringMaps(desired.tags, spec.GetTags()) {
+		mask = append(mask, "spec.tags")
+	}
+	return mask
+}
+
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// specUint32 reads a YAML integer into a uint32. gopkg.in/yaml.v3 decodes
+// YAML integers into interface{} as int (not uint32), so a bare type
+// assertion to uint32 always misses; this also accepts float64 in case the
+// value ever comes from a JSON-sourced map instead of YAML.
+func specUint32(v interface{}) (uint32, bool) {
+	switch n := v.(type) {
+	case int:
+		return uint32(n), true
+	case int64:
+		return uint32(n), true
+	case float64:
+		return uint32(n), true
+	default:
+		return 0, false
+	}
+}
+
+// specStringSlice reads a YAML sequence of scalars into a string slice,
+// tolerating the absence of the field.
+func specStringSlice(v interface{}) []string {
+	raw, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// specStringMap reads a YAML mapping of scalars into a string map,
+// tolerating the absence of the field.
+func specStringMap(v interface{}) map[string]string {
+	raw, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for k, val := range raw {
+		if s, ok := val.(string); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+// planf prints a planned action. In dry-run mode this is the only output;
+// otherwise it's a progress line ahead of the actual gRPC call.
+func planf(dryRun bool, kind, name, action string) {
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] "
+	}
+	log.Printf("%s%s %q: %s", prefix, kind, name, action)
+}
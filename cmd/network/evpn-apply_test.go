@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+package network
+
+import (
+	"testing"
+
+	"github.com/opiproject/godpu/network"
+)
+
+// TestVrfUpdateMask exercises vrfUpdateMask with a real *network.Vrf rather
+// than a fake satisfying a hand-written interface, since that's exactly what
+// let the GetSpec()-return-type mismatch bug slip through: a fake built to
+// satisfy a hand-written getter interface always worked, while the generated
+// type never did.
+func TestVrfUpdateMask(t *testing.T) {
+	existing := &network.Vrf{
+		Spec: &network.VrfSpec{
+			Loopback: "10.0.0.1",
+			Vtep:     "10.0.0.2",
+		},
+	}
+	desired := vrfDesired{
+		loopback: "10.0.0.1",
+		vtep:     "10.0.0.3",
+	}
+
+	mask := vrfUpdateMask(existing, desired)
+
+	if len(mask) != 1 || mask[0] != "spec.vtep" {
+		t.Fatalf("expected mask [spec.vtep] for a drifted vtep, got %v", mask)
+	}
+}
+
+// TestBridgePortUpdateMask is the BridgePort analogue of TestVrfUpdateMask.
+func TestBridgePortUpdateMask(t *testing.T) {
+	existing := &network.BridgePort{
+		Spec: &network.BridgePortSpec{
+			LogicalBridge: "br0",
+			MacAddress:    "aa:bb:cc:dd:ee:ff",
+		},
+	}
+	desired := bridgePortDesired{
+		logicalBridge: "br0",
+		macAddress:    "11:22:33:44:55:66",
+	}
+
+	mask := bridgePortUpdateMask(existing, desired)
+
+	if len(mask) != 1 || mask[0] != "spec.mac_address" {
+		t.Fatalf("expected mask [spec.mac_address] for a drifted mac address, got %v", mask)
+	}
+}
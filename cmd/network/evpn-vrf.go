@@ -12,24 +12,52 @@ import (
 	"time"
 
 	"github.com/opiproject/godpu/network"
+	"github.com/opiproject/godpu/pkg/cliconfig"
+	"github.com/opiproject/godpu/pkg/cliformat"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 )
 
 // CreateVRF Create vrf on OPI Server
 func CreateVRF() *cobra.Command {
-	var addr string
 	var name string
 	var vni uint32
 	var loopback string
 	var vtep string
+	var localASN uint32
+	var importRT []string
+	var exportRT []string
+	var ipRange []string
+	var description string
+	var tags map[string]string
+	var dialFlags *cliconfig.DialFlags
+	var printer *cliformat.Printer
 
 	cmd := &cobra.Command{
 		Use:   "create-vrf",
 		Short: "Create a VRF",
-		Run: func(_ *cobra.Command, _ []string) {
+		Run: func(c *cobra.Command, _ []string) {
 			var vniparam *uint32
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			evpnClient, err := network.NewVRF(addr)
+			if err := validateRouteTargets(importRT); err != nil {
+				log.Fatalf("invalid --import-rt: %v", err)
+			}
+			if err := validateRouteTargets(exportRT); err != nil {
+				log.Fatalf("invalid --export-rt: %v", err)
+			}
+			if err := validateIPRanges(ipRange); err != nil {
+				log.Fatalf("invalid --ip-range: %v", err)
+			}
+
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			evpnClient, err := network.NewVRF(profile.Addr, dialOpts...)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
@@ -37,12 +65,19 @@ func CreateVRF() *cobra.Command {
 			if vni != 0 {
 				vniparam = &vni
 			}
-			vrf, err := evpnClient.CreateVrf(ctx, name, vniparam, loopback, vtep)
+			vrf, err := evpnClient.CreateVrf(ctx, name, vniparam, loopback, vtep,
+				localASN, importRT, exportRT, ipRange, description, tags)
 			if err != nil {
 				log.Fatalf("failed to create vrf: %v", err)
 			}
-			log.Println("Created VRF:")
-			PrintVrf(vrf)
+			if printer.IsTable() {
+				log.Println("Created VRF:")
+				PrintVrf(vrf)
+				return
+			}
+			if err := printer.Print(vrf); err != nil {
+				log.Fatalf("failed to print vrf: %v", err)
+			}
 		},
 	}
 
@@ -50,7 +85,14 @@ func CreateVRF() *cobra.Command {
 	cmd.Flags().Uint32VarP(&vni, "vni", "v", 0, "Must be unique ")
 	cmd.Flags().StringVar(&loopback, "loopback", "", "Loopback IP address")
 	cmd.Flags().StringVar(&vtep, "vtep", "", "VTEP IP address")
-	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
+	cmd.Flags().Uint32Var(&localASN, "local-asn", 0, "Local BGP AS number for this VRF")
+	cmd.Flags().StringSliceVar(&importRT, "import-rt", nil, "Route target to import, in asn:val form (repeatable)")
+	cmd.Flags().StringSliceVar(&exportRT, "export-rt", nil, "Route target to export, in asn:val form (repeatable)")
+	cmd.Flags().StringSliceVar(&ipRange, "ip-range", nil, "CIDR range routable within this VRF (repeatable)")
+	cmd.Flags().StringVar(&description, "description", "", "Free-form description")
+	cmd.Flags().StringToStringVar(&tags, "tag", nil, "Tag in key=value form (repeatable)")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+	printer = cliformat.AddFlag(cmd)
 
 	if err := cmd.MarkFlagRequired("loopback"); err != nil {
 		log.Fatalf("Error marking flag as required: %v", err)
@@ -60,16 +102,24 @@ func CreateVRF() *cobra.Command {
 
 // DeleteVRF update the vrf on OPI server
 func DeleteVRF() *cobra.Command {
-	var addr string
 	var name string
 	var allowMissing bool
+	var dialFlags *cliconfig.DialFlags
 
 	cmd := &cobra.Command{
 		Use:   "delete-vrf",
 		Short: "Delete a VRF",
-		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			evpnClient, err := network.NewVRF(addr)
+		Run: func(c *cobra.Command, _ []string) {
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			evpnClient, err := network.NewVRF(profile.Addr, dialOpts...)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
@@ -86,22 +136,31 @@ func DeleteVRF() *cobra.Command {
 
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the BridgePort")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "Specify the name of the BridgePort")
-	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
+	dialFlags = cliconfig.AddDialFlags(cmd)
 
 	return cmd
 }
 
 // GetVRF get vrf details from OPI server
 func GetVRF() *cobra.Command {
-	var addr string
 	var name string
+	var dialFlags *cliconfig.DialFlags
+	var printer *cliformat.Printer
 
 	cmd := &cobra.Command{
 		Use:   "get-vrf",
 		Short: "Show details of a VRF",
-		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			evpnClient, err := network.NewVRF(addr)
+		Run: func(c *cobra.Command, _ []string) {
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			evpnClient, err := network.NewVRF(profile.Addr, dialOpts...)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
@@ -113,13 +172,20 @@ func GetVRF() *cobra.Command {
 				log.Fatalf("DeleteVRF: Error occurred while creating Bridge Port: %q", err)
 			}
 
-			log.Println("Get VRF:")
-			PrintVrf(vrf)
+			if printer.IsTable() {
+				log.Println("Get VRF:")
+				PrintVrf(vrf)
+				return
+			}
+			if err := printer.Print(vrf); err != nil {
+				log.Fatalf("failed to print vrf: %v", err)
+			}
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the vrf")
-	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+	printer = cliformat.AddFlag(cmd)
 
 	if err := cmd.MarkFlagRequired("name"); err != nil {
 		log.Fatalf("Error marking flag as required: %v", err)
@@ -129,30 +195,50 @@ func GetVRF() *cobra.Command {
 
 // ListVRFs list all vrf's with details from OPI server
 func ListVRFs() *cobra.Command {
-	var addr string
 	var pageSize int32
 	var pageToken string
+	var dialFlags *cliconfig.DialFlags
+	var printer *cliformat.Printer
 
 	cmd := &cobra.Command{
 		Use:   "list-vrfs",
 		Short: "Show details of all Vrfs",
-		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			evpnClient, err := network.NewVRF(addr)
+		Run: func(c *cobra.Command, _ []string) {
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			if !c.Flags().Changed("pagesize") && profile.DefaultPageSize != 0 {
+				pageSize = profile.DefaultPageSize
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			evpnClient, err := network.NewVRF(profile.Addr, dialOpts...)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
+
+			var all []proto.Message
 			for {
 				resp, err := evpnClient.ListVrfs(ctx, pageSize, pageToken)
 				if err != nil {
 					log.Fatalf("Failed to get items: %v", err)
 				}
-				// Process the server response
-				log.Println("list VRFs:")
-				for _, vrf := range resp.Vrfs {
-					log.Println("VRF with:")
-					PrintVrf(vrf)
+
+				if printer.IsTable() {
+					log.Println("list VRFs:")
+					for _, vrf := range resp.Vrfs {
+						log.Println("VRF with:")
+						PrintVrf(vrf)
+					}
+				} else {
+					for _, vrf := range resp.Vrfs {
+						all = append(all, vrf)
+					}
 				}
 
 				// Check if there are more pages to retrieve
@@ -163,45 +249,119 @@ func ListVRFs() *cobra.Command {
 				// Update the page token for the next request
 				pageToken = resp.NextPageToken
 			}
+
+			if !printer.IsTable() {
+				if err := printer.PrintAll(all); err != nil {
+					log.Fatalf("failed to print vrfs: %v", err)
+				}
+			}
 		},
 	}
 	cmd.Flags().Int32VarP(&pageSize, "pagesize", "s", 0, "Specify page size")
 	cmd.Flags().StringVarP(&pageToken, "pagetoken", "t", "", "Specify the token")
-	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+	printer = cliformat.AddFlag(cmd)
 	return cmd
 }
 
+// updateMaskFlags maps a CLI flag name to the proto field path it updates,
+// for the flags UpdateVRF can auto-compute a FieldMask from.
+var updateMaskFlags = map[string]string{
+	"loopback":    "spec.loopback",
+	"vtep":        "spec.vtep",
+	"local-asn":   "spec.local_asn",
+	"import-rt":   "spec.import_rt",
+	"export-rt":   "spec.export_rt",
+	"ip-range":    "spec.ip_range",
+	"description": "spec.description",
+	"tag":         "spec.tags",
+}
+
 // UpdateVRF update the vrf on OPI server
 func UpdateVRF() *cobra.Command {
-	var addr string
 	var name string
 	var updateMask []string
 	var allowMissing bool
+	var loopback string
+	var vtep string
+	var localASN uint32
+	var importRT []string
+	var exportRT []string
+	var ipRange []string
+	var description string
+	var tags map[string]string
+	var dialFlags *cliconfig.DialFlags
+	var printer *cliformat.Printer
 
 	cmd := &cobra.Command{
 		Use:   "update-vrf",
 		Short: "update the VRF",
-		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			evpnClient, err := network.NewVRF(addr)
+		Run: func(c *cobra.Command, _ []string) {
+			if err := validateRouteTargets(importRT); err != nil {
+				log.Fatalf("invalid --import-rt: %v", err)
+			}
+			if err := validateRouteTargets(exportRT); err != nil {
+				log.Fatalf("invalid --export-rt: %v", err)
+			}
+			if err := validateIPRanges(ipRange); err != nil {
+				log.Fatalf("invalid --ip-range: %v", err)
+			}
+
+			// Auto-compute the FieldMask from whichever flags the user
+			// actually set, unless they spelled --update-mask out by hand.
+			if !c.Flags().Changed("update-mask") {
+				updateMask = nil
+				for flag, path := range updateMaskFlags {
+					if c.Flags().Changed(flag) {
+						updateMask = append(updateMask, path)
+					}
+				}
+			}
+
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			evpnClient, err := network.NewVRF(profile.Addr, dialOpts...)
 			if err != nil {
 				log.Fatalf("could not create gRPC client: %v", err)
 			}
 			defer cancel()
 
 			// grpc call to create the bridge port
-			vrf, err := evpnClient.UpdateVrf(ctx, name, updateMask, allowMissing)
+			vrf, err := evpnClient.UpdateVrf(ctx, name, updateMask, allowMissing,
+				loopback, vtep, localASN, importRT, exportRT, ipRange, description, tags)
 			if err != nil {
 				log.Fatalf("GetBridgePort: Error occurred while creating Bridge Port: %q", err)
 			}
-			log.Println("Updated VRF:")
-			PrintVrf(vrf)
+			if printer.IsTable() {
+				log.Println("Updated VRF:")
+				PrintVrf(vrf)
+				return
+			}
+			if err := printer.Print(vrf); err != nil {
+				log.Fatalf("failed to print vrf: %v", err)
+			}
 		},
 	}
-	cmd.Flags().StringVar(&addr, "addr", "localhost:50151", "address of OPI gRPC server")
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify the name of the vrf")
-	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask")
+	cmd.Flags().StringSliceVar(&updateMask, "update-mask", nil, "update mask (auto-computed from the flags you set if omitted)")
 	cmd.Flags().BoolVarP(&allowMissing, "allowMissing", "a", false, "allow the missing")
+	cmd.Flags().StringVar(&loopback, "loopback", "", "Loopback IP address")
+	cmd.Flags().StringVar(&vtep, "vtep", "", "VTEP IP address")
+	cmd.Flags().Uint32Var(&localASN, "local-asn", 0, "Local BGP AS number for this VRF")
+	cmd.Flags().StringSliceVar(&importRT, "import-rt", nil, "Route target to import, in asn:val form (repeatable)")
+	cmd.Flags().StringSliceVar(&exportRT, "export-rt", nil, "Route target to export, in asn:val form (repeatable)")
+	cmd.Flags().StringSliceVar(&ipRange, "ip-range", nil, "CIDR range routable within this VRF (repeatable)")
+	cmd.Flags().StringVar(&description, "description", "", "Free-form description")
+	cmd.Flags().StringToStringVar(&tags, "tag", nil, "Tag in key=value form (repeatable)")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+	printer = cliformat.AddFlag(cmd)
 
 	return cmd
 }
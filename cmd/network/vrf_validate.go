@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2022-2023 Intel Corporation, or its subsidiaries.
+// Copyright (c) 2022-2023 Dell Inc, or its subsidiaries.
+// Copyright (c) 2024 Ericsson AB.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// validateRouteTargets checks that every route target is in Equinix Metal's
+// `asn:val` form, e.g. "65000:100" or "10.0.0.1:100".
+func validateRouteTargets(rts []string) error {
+	for _, rt := range rts {
+		parts := strings.SplitN(rt, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("route target %q is not in asn:val form", rt)
+		}
+		if _, err := strconv.ParseUint(parts[1], 10, 32); err != nil {
+			return fmt.Errorf("route target %q: val must be numeric: %w", rt, err)
+		}
+		if net.ParseIP(parts[0]) == nil {
+			if _, err := strconv.ParseUint(parts[0], 10, 32); err != nil {
+				return fmt.Errorf("route target %q: asn must be a numeric AS number or an IP address", rt)
+			}
+		}
+	}
+	return nil
+}
+
+// validateIPRanges checks that every range is a valid CIDR and that no two
+// ranges overlap.
+func validateIPRanges(ranges []string) error {
+	parsed := make([]*net.IPNet, 0, len(ranges))
+	for _, r := range ranges {
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			return fmt.Errorf("ip range %q is not a valid CIDR: %w", r, err)
+		}
+		for _, other := range parsed {
+			if cidrsOverlap(ipNet, other) {
+				return fmt.Errorf("ip range %q overlaps with %q", r, other.String())
+			}
+		}
+		parsed = append(parsed, ipNet)
+	}
+	return nil
+}
+
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
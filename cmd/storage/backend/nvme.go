@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+package backend
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/opiproject/godpu/pkg/cliconfig"
+	"github.com/opiproject/godpu/pkg/cliformat"
+	"github.com/opiproject/godpu/storage"
+	"github.com/spf13/cobra"
+)
+
+// newCreateNvmeControllerCommand creates a new command to create an NVMe
+// controller backend resource.
+func newCreateNvmeControllerCommand() *cobra.Command {
+	var id string
+	var pcieAddress string
+	var maxIoQueues uint32
+	var dialFlags *cliconfig.DialFlags
+	var printer *cliformat.Printer
+
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Aliases: []string{"c"},
+		Short:   "Creates an NVMe controller backend resource",
+		Args:    cobra.NoArgs,
+		Run: func(c *cobra.Command, _ []string) {
+			profile, err := cliconfig.Dial(c, dialFlags)
+			if err != nil {
+				log.Fatalf("could not resolve connection profile: %v", err)
+			}
+			dialOpts, err := profile.DialOptions()
+			if err != nil {
+				log.Fatalf("could not build dial options: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), profile.DialTimeout(10*time.Second))
+			defer cancel()
+
+			nvmeClient, err := storage.NewNvme(profile.Addr, dialOpts...)
+			if err != nil {
+				log.Fatalf("could not create gRPC client: %v", err)
+			}
+
+			ctrl, err := nvmeClient.CreateNvmeController(ctx, id, pcieAddress, maxIoQueues)
+			if err != nil {
+				log.Fatalf("failed to create nvme controller: %v", err)
+			}
+			if printer.IsTable() {
+				log.Printf("Created NVMe controller: %s\n", ctrl.GetName())
+				return
+			}
+			if err := printer.Print(ctrl); err != nil {
+				log.Fatalf("failed to print nvme controller: %v", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&id, "id", "i", "", "NVMe controller resource ID")
+	cmd.Flags().StringVar(&pcieAddress, "pcie-address", "", "PCIe address (BDF) the controller should attach to")
+	cmd.Flags().Uint32Var(&maxIoQueues, "max-io-queues", 0, "Maximum number of I/O queues the controller supports")
+	dialFlags = cliconfig.AddDialFlags(cmd)
+	printer = cliformat.AddFlag(cmd)
+
+	if err := cmd.MarkFlagRequired("id"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+	if err := cmd.MarkFlagRequired("pcie-address"); err != nil {
+		log.Fatalf("Error marking flag as required: %v", err)
+	}
+	return cmd
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// godpuBinary is the path to the compiled CLI under test, built once by
+// TestMain so every scenario in this package reuses the same binary.
+var godpuBinary string
+
+func TestMain(m *testing.M) {
+	bin, cleanup, err := buildGodpu()
+	if err != nil {
+		panic(err)
+	}
+	godpuBinary = bin
+	code := m.Run()
+	cleanup()
+	os.Exit(code)
+}
+
+// buildGodpu compiles the godpu CLI from the repository root into a
+// temporary directory, so the e2e suite always exercises the code under
+// test rather than a binary that happens to be on $PATH. e2e is its own Go
+// module (see e2e/go.mod), so the build can't reach the root module via a
+// relative ".." package path from inside e2e; instead it runs with the root
+// module's directory as the working directory, where the root go.mod is in
+// scope.
+func buildGodpu() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "godpu-e2e-*")
+	if err != nil {
+		return "", nil, err
+	}
+	bin := filepath.Join(dir, "godpu")
+
+	root, err := filepath.Abs("..")
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("go build in %s failed: %w\n%s", root, err, out)
+	}
+	return bin, func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// TestVrfBridgeSviLifecycle exercises the full create -> list -> delete
+// sequence a real operator would run by hand. Every object, the VRF
+// included, is created by `apply` and removed by `destroy` against
+// topo/evpn-fabric.yaml; `list-vrfs` and the vrf0 device inside the server
+// container are checked in between.
+func TestVrfBridgeSviLifecycle(t *testing.T) {
+	topo, err := LoadTopology(filepath.Join("topo", "evpn-vrf-bridge-svi.yaml"))
+	require.NoError(t, err)
+
+	spec, ok := topo.ContainerByName("opi-server")
+	require.True(t, ok, "topology must declare an opi-server container")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	req := testcontainers.ContainerRequest{
+		Image:        spec.Image,
+		Privileged:   spec.Privileged,
+		ExposedPorts: []string{"50151/tcp"},
+		WaitingFor:   wait.ForListeningPort("50151/tcp").WithStartupTimeout(60 * time.Second),
+	}
+	server, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, server.Terminate(ctx)) })
+
+	addr, err := server.PortEndpoint(ctx, "50151", "")
+	require.NoError(t, err)
+
+	run := func(args ...string) (string, string) {
+		cmd := exec.CommandContext(ctx, godpuBinary, args...)
+		var stdout, stderr strings.Builder
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		require.NoError(t, err, "godpu %v failed: stderr=%s", args, stderr.String())
+		return stdout.String(), stderr.String()
+	}
+
+	fabric := filepath.Join("topo", "evpn-fabric.yaml")
+
+	run("network", "apply", "--addr", addr, "-f", fabric)
+
+	listOut, _ := run("network", "list-vrfs", "--addr", addr, "-o", "json")
+	require.Contains(t, listOut, "vrf0")
+
+	code, stdout, err := server.Exec(ctx, []string{"ip", "-d", "link", "show", "vrf0"})
+	require.NoError(t, err)
+	require.Equal(t, 0, code, "expected a vrf0 device inside the server container, got: %v", stdout)
+
+	run("network", "destroy", "--addr", addr, "-f", fabric)
+}
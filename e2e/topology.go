@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package e2e runs the godpu CLI as a subprocess against a real OPI gRPC
+// server and network namespaces, to catch regressions in the sequences of
+// gRPC calls that the unit tests mock away.
+package e2e
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Topology describes the containers, namespaces and veth pairs an e2e
+// scenario needs, modeled on the VPP hs-test framework: scenarios are data
+// (YAML files under e2e/topo/) rather than code, so adding a new EVPN
+// scenario doesn't require touching the test harness itself.
+type Topology struct {
+	// Containers are the Docker/Podman containers the scenario starts,
+	// keyed by the role name used to refer to them from Namespaces.
+	Containers []ContainerSpec `yaml:"containers"`
+	// Namespaces are the network namespaces created inside a container.
+	Namespaces []NamespaceSpec `yaml:"namespaces"`
+	// Veths are the veth pairs wired between namespaces/containers.
+	Veths []VethSpec `yaml:"veths"`
+}
+
+// ContainerSpec is one container role participating in the scenario, e.g.
+// the OPI gRPC server under test or a Linux host acting as a VTEP peer.
+type ContainerSpec struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+	// Privileged containers are needed for roles that manipulate netlink
+	// state directly (VRF devices, VXLAN interfaces, bridges).
+	Privileged bool `yaml:"privileged"`
+}
+
+// NamespaceSpec names a network namespace inside a container. The harness
+// parses it but does not create the namespace.
+type NamespaceSpec struct {
+	Name      string `yaml:"name"`
+	Container string `yaml:"container"`
+}
+
+// VethSpec is a veth pair connecting two namespaces, with each end's address.
+// The harness parses it but does not create the pair or assert on it.
+type VethSpec struct {
+	Name  string       `yaml:"name"`
+	Left  VethEndpoint `yaml:"left"`
+	Right VethEndpoint `yaml:"right"`
+}
+
+// VethEndpoint is one side of a veth pair.
+type VethEndpoint struct {
+	Namespace string `yaml:"namespace"`
+	IfName    string `yaml:"ifName"`
+	Address   string `yaml:"address"`
+}
+
+// LoadTopology reads a scenario definition from the YAML file at path, e.g.
+// topo/evpn-vrf-bridge-svi.yaml.
+func LoadTopology(path string) (*Topology, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // test topology path is a fixed fixture path, not user input
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology %q: %w", path, err)
+	}
+
+	var topo Topology
+	if err := yaml.Unmarshal(data, &topo); err != nil {
+		return nil, fmt.Errorf("failed to parse topology %q: %w", path, err)
+	}
+	return &topo, nil
+}
+
+// ContainerByName looks up a container role declared in the topology.
+func (t *Topology) ContainerByName(name string) (ContainerSpec, bool) {
+	for _, c := range t.Containers {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ContainerSpec{}, false
+}
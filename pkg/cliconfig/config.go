@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package cliconfig stores named godpu connection profiles (gRPC address,
+// TLS material, timeouts) in $XDG_CONFIG_HOME/godpu/config.yaml, so that
+// commands don't have to redeclare --addr and TLS flags on every invocation.
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/file"
+	"sigs.k8s.io/yaml"
+)
+
+// configKey is the single gokv key the whole config file is stored under.
+// Using one key instead of one per profile keeps `godpu context` reads and
+// writes atomic.
+const configKey = "config.yaml"
+
+// TLS holds the certificate material for an mTLS connection to an OPI
+// server.
+type TLS struct {
+	CertFile           string `json:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty"`
+	CAFile             string `json:"caFile,omitempty"`
+	ServerNameOverride string `json:"serverNameOverride,omitempty"`
+}
+
+// Profile is one named connection endpoint.
+type Profile struct {
+	Addr            string        `json:"addr"`
+	TLS             *TLS          `json:"tls,omitempty"`
+	Timeout         time.Duration `json:"timeout,omitempty"`
+	DefaultPageSize int32         `json:"defaultPageSize,omitempty"`
+}
+
+// Config is the persisted shape of config.yaml.
+type Config struct {
+	CurrentContext string             `json:"currentContext"`
+	Contexts       map[string]Profile `json:"contexts"`
+}
+
+var yamlCodec = gokv.Codec{Marshal: yaml.Marshal, Unmarshal: yaml.Unmarshal}
+
+// configDir returns $XDG_CONFIG_HOME/godpu, falling back to ~/.config/godpu.
+func configDir() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home directory: %w", err)
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "godpu"), nil
+}
+
+// newStore opens the gokv file-backed store config.yaml lives in. gokv is
+// used here (rather than reading/writing the file directly) so a future
+// chunk can swap in a different backend, e.g. a shared store for CI
+// runners, without touching the callers below.
+func newStore() (gokv.Store, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("could not create config directory %q: %w", dir, err)
+	}
+	return file.NewStore(file.Options{Directory: dir, Codec: yamlCodec})
+}
+
+// Load reads the config file, returning an empty Config if none has been
+// written yet (e.g. the first time `godpu context set` runs).
+func Load() (*Config, error) {
+	s, err := newStore()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = s.Close() }()
+
+	cfg := &Config{Contexts: map[string]Profile{}}
+	found, err := s.Get(configKey, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+	if !found {
+		return &Config{Contexts: map[string]Profile{}}, nil
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = map[string]Profile{}
+	}
+	return cfg, nil
+}
+
+// Save persists cfg to the config file.
+func Save(cfg *Config) error {
+	s, err := newStore()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	if err := s.Set(configKey, cfg); err != nil {
+		return fmt.Errorf("could not write config: %w", err)
+	}
+	return nil
+}
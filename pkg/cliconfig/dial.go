@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+package cliconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// contextEnvVar overrides the active context for a single invocation,
+// without having to run `godpu context use`.
+const contextEnvVar = "GODPU_CONTEXT"
+
+// DialFlags are the flags every gRPC-backed command registers so it can
+// still be pointed at an ad hoc server without a saved context.
+type DialFlags struct {
+	addr          string
+	tlsCertFile   string
+	tlsKeyFile    string
+	tlsCAFile     string
+	tlsServerName string
+	contextName   string
+}
+
+// AddDialFlags registers --addr, --tls-*, and --context on cmd.
+func AddDialFlags(cmd *cobra.Command) *DialFlags {
+	f := &DialFlags{}
+	cmd.Flags().StringVar(&f.addr, "addr", "", "address of OPI gRPC server (overrides the active context)")
+	cmd.Flags().StringVar(&f.tlsCertFile, "tls-cert", "", "client certificate for mTLS")
+	cmd.Flags().StringVar(&f.tlsKeyFile, "tls-key", "", "client key for mTLS")
+	cmd.Flags().StringVar(&f.tlsCAFile, "tls-ca", "", "CA bundle used to verify the server certificate")
+	cmd.Flags().StringVar(&f.tlsServerName, "tls-server-name", "", "override the server name verified in the TLS certificate")
+	cmd.Flags().StringVar(&f.contextName, "context", "", "named context to use instead of the active one")
+	return f
+}
+
+// dialFlagNames are the flags Resolve checks to decide whether the caller
+// is overriding the saved context for this one invocation.
+var dialFlagNames = []string{"addr", "tls-cert", "tls-key", "tls-ca", "tls-server-name"}
+
+// Resolve determines the effective profile for a command invocation, in
+// order: explicit --addr/--tls-* flags, then --context/GODPU_CONTEXT, then
+// the active context from the config file, then the localhost:50151
+// default this CLI has always shipped with.
+func (f *DialFlags) Resolve(cmd *cobra.Command) (Profile, error) {
+	if f.explicitFlagsChanged(cmd) {
+		return f.explicitProfile(), nil
+	}
+
+	name := f.contextName
+	if name == "" {
+		name = os.Getenv(contextEnvVar)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return Profile{}, err
+	}
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return f.explicitProfile(), nil
+	}
+
+	profile, ok := cfg.Contexts[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("context %q not found; run %q to see what's available", name, "godpu context list")
+	}
+	return profile, nil
+}
+
+// explicitFlagsChanged reports whether the caller set --addr or any --tls-*
+// flag explicitly. Gating on --addr alone meant a one-off --tls-cert (say,
+// to use a different client cert against the active context's address) was
+// silently dropped because Resolve fell through to the saved context
+// instead of building a profile from the flags actually passed.
+func (f *DialFlags) explicitFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range dialFlagNames {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// explicitProfile builds a Profile from whatever --addr/--tls-* flags were
+// passed directly, falling back to the CLI's historical default address.
+func (f *DialFlags) explicitProfile() Profile {
+	addr := f.addr
+	if addr == "" {
+		addr = "localhost:50151"
+	}
+	p := Profile{Addr: addr}
+	if f.tlsCertFile != "" || f.tlsKeyFile != "" || f.tlsCAFile != "" || f.tlsServerName != "" {
+		p.TLS = &TLS{
+			CertFile:           f.tlsCertFile,
+			KeyFile:            f.tlsKeyFile,
+			CAFile:             f.tlsCAFile,
+			ServerNameOverride: f.tlsServerName,
+		}
+	}
+	return p
+}
+
+// Dial resolves the effective profile for cmd and returns it so the caller
+// can pass profile.Addr and profile.DialOptions() to its gRPC client
+// constructor.
+func Dial(cmd *cobra.Command, flags *DialFlags) (Profile, error) {
+	return flags.Resolve(cmd)
+}
+
+// DialOptions turns p's TLS settings into gRPC dial options: mTLS
+// credentials built from CertFile/KeyFile/CAFile/ServerNameOverride if TLS
+// is set, or plaintext credentials (this CLI's historical default)
+// otherwise.
+func (p Profile) DialOptions() ([]grpc.DialOption, error) {
+	if p.TLS == nil {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+
+	conf := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: p.TLS.ServerNameOverride}
+
+	if p.TLS.CAFile != "" {
+		ca, err := os.ReadFile(p.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle %q: %w", p.TLS.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %q", p.TLS.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if p.TLS.CertFile != "" || p.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.TLS.CertFile, p.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate %q/%q: %w", p.TLS.CertFile, p.TLS.KeyFile, err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(conf))}, nil
+}
+
+// DialTimeout returns p's configured timeout, or def if the profile (or the
+// ad hoc explicit profile built from flags) doesn't set one.
+func (p Profile) DialTimeout(def time.Duration) time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return def
+}
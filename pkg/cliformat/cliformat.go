@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2024 Intel Corporation
+
+// Package cliformat provides a shared --output/-o flag for godpu CLI
+// commands, modeled on the podman formats package: every command that
+// renders an OPI proto message can opt into "table" (its own existing
+// human-readable output), "json", "yaml", or a user-supplied Go template.
+package cliformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const templatePrefix = "template="
+
+// Printer renders proto.Message values in the format selected by the shared
+// --output/-o flag. The zero value is not usable; create one with AddFlag.
+type Printer struct {
+	output string
+}
+
+// AddFlag registers the shared --output/-o flag on cmd and returns the
+// Printer that reads its resolved value once cmd has parsed its arguments.
+func AddFlag(cmd *cobra.Command) *Printer {
+	p := &Printer{}
+	cmd.Flags().StringVarP(&p.output, "output", "o", "table",
+		`Output format: "table" (default), "json", "yaml", or "template=<go-template>"`)
+	return p
+}
+
+// IsTable reports whether the user asked for the command's own
+// human-readable rendering rather than a structured format. Commands should
+// fall back to their existing log.Println/Print* behavior in this case.
+func (p *Printer) IsTable() bool {
+	return p.output == "" || p.output == "table"
+}
+
+// Print marshals a single message to stdout in the selected structured
+// format. It must only be called when IsTable() returns false.
+func (p *Printer) Print(msg proto.Message) error {
+	return p.PrintAll([]proto.Message{msg})
+}
+
+// PrintAll marshals a slice of messages to stdout as a single JSON or YAML
+// array (so e.g. ListVRFs can emit one array across all paginated pages), or
+// executes the user's template once per message. It must only be called when
+// IsTable() returns false.
+func (p *Printer) PrintAll(msgs []proto.Message) error {
+	switch {
+	case p.output == "json":
+		return p.printJSON(msgs)
+	case p.output == "yaml":
+		return p.printYAML(msgs)
+	case strings.HasPrefix(p.output, templatePrefix):
+		return p.printTemplate(msgs, strings.TrimPrefix(p.output, templatePrefix))
+	default:
+		return fmt.Errorf("unknown output format %q, expected table, json, yaml, or template=<go-template>", p.output)
+	}
+}
+
+func (p *Printer) printJSON(msgs []proto.Message) error {
+	raw := make([]json.RawMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message as JSON: %w", err)
+		}
+		raw = append(raw, b)
+	}
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result as JSON: %w", err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func (p *Printer) printYAML(msgs []proto.Message) error {
+	raw := make([]json.RawMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message as JSON: %w", err)
+		}
+		raw = append(raw, b)
+	}
+	jsonArr, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result as JSON: %w", err)
+	}
+	out, err := k8syaml.JSONToYAML(jsonArr)
+	if err != nil {
+		return fmt.Errorf("failed to convert result to YAML: %w", err)
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// templateFuncs exposes ID and Name as template functions, called as
+// {{ID .}} and {{Name .}}, for OPI messages whose identifying field is only
+// reachable through a generated getter.
+var templateFuncs = template.FuncMap{
+	"ID": func(msg interface{ GetId() string }) string { return msg.GetId() },
+	"Name": func(msg interface{ GetName() string }) string { return msg.GetName() },
+}
+
+func (p *Printer) printTemplate(msgs []proto.Message, text string) error {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --output template: %w", err)
+	}
+	for _, msg := range msgs {
+		if err := tmpl.Execute(os.Stdout, msg); err != nil {
+			return fmt.Errorf("failed to execute --output template: %w", err)
+		}
+		fmt.Println()
+	}
+	return nil
+}